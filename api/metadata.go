@@ -0,0 +1,27 @@
+package api
+
+import (
+	"errors"
+	"github.com/alancesar/photo-gallery/thumbs/image"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// GetPhotoMetadataHandler returns the EXIF, dominant color and pixel
+// dimensions extracted for a previously processed photo.
+func GetPhotoMetadataHandler(db *image.Database) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		metadata, err := db.FindMetadata(c.Request.Context(), c.Param("filename"))
+		if err != nil {
+			if errors.Is(err, image.ErrMetadataNotFound) {
+				c.Status(http.StatusNotFound)
+				return
+			}
+
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+
+		c.JSON(http.StatusOK, metadata)
+	}
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"errors"
+	"github.com/alancesar/photo-gallery/thumbs/config"
+	"github.com/alancesar/photo-gallery/thumbs/domain/thumb"
+	"github.com/alancesar/photo-gallery/thumbs/image"
+	"github.com/alancesar/photo-gallery/thumbs/imgproxy"
+	"github.com/alancesar/photo-gallery/thumbs/storage"
+	"github.com/gin-gonic/gin"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultQuality  = 80
+	presignedURLTTL = 15 * time.Minute
+	formatAuto      = "auto"
+)
+
+// negotiateFormat picks the richest encoding the client's Accept header
+// advertises support for, falling back to JPEG for anything else.
+func negotiateFormat(accept string) string {
+	switch {
+	case strings.Contains(accept, "image/avif"):
+		return "avif"
+	case strings.Contains(accept, "image/webp"):
+		return "webp"
+	default:
+		return "jpeg"
+	}
+}
+
+// GetThumbsHandler serves a redirect (or proxied response) to the
+// requested filename/height/quality/format. Photos processed in on-demand
+// mode resolve to an imgproxy-signed URL computed on the fly; photos
+// processed eagerly resolve to a presigned URL for the already-rendered
+// object in thumbStorage.
+func GetThumbsHandler(db *image.Database, thumbStorage storage.ObjectStore, signer *imgproxy.Signer, photosBucket string, proxy bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		filename := c.Param("filename")
+
+		photo, err := db.FindByFilename(c.Request.Context(), filename)
+		if err != nil {
+			if errors.Is(err, image.ErrPhotoNotFound) {
+				c.Status(http.StatusNotFound)
+				return
+			}
+
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+
+		if photo.Mode == config.ModeRejected {
+			c.JSON(http.StatusUnsupportedMediaType, gin.H{"reason": photo.Reason})
+			return
+		}
+
+		height, err := strconv.Atoi(c.Param("height"))
+		if err != nil {
+			c.Status(http.StatusBadRequest)
+			return
+		}
+
+		quality := defaultQuality
+		if raw := c.Param("quality"); raw != "" {
+			quality, err = strconv.Atoi(raw)
+			if err != nil {
+				c.Status(http.StatusBadRequest)
+				return
+			}
+		}
+
+		format := c.Param("format")
+		if format == formatAuto {
+			format = negotiateFormat(c.GetHeader("Accept"))
+		}
+
+		var signedURL string
+		if photo.Mode == config.ModeEager {
+			// Eager thumbnails were rendered and keyed at image.DefaultQuality
+			// regardless of what's requested here, so the presign key must
+			// match that rather than the client-supplied quality.
+			signedURL, err = thumbStorage.PresignGet(c.Request.Context(), thumb.Key(filename, height, image.DefaultQuality, format), presignedURLTTL)
+			if err != nil {
+				c.Status(http.StatusInternalServerError)
+				return
+			}
+		} else {
+			processingPath := imgproxy.ProcessingPath(photosBucket, filename, height, quality, format)
+			signedURL = signer.SignedURL(processingPath)
+		}
+
+		if !proxy {
+			c.Redirect(http.StatusFound, signedURL)
+			return
+		}
+
+		resp, err := http.Get(signedURL)
+		if err != nil {
+			c.Status(http.StatusBadGateway)
+			return
+		}
+		defer resp.Body.Close()
+
+		c.Status(resp.StatusCode)
+		c.Header("Content-Type", resp.Header.Get("Content-Type"))
+		_, _ = io.Copy(c.Writer, resp.Body)
+	}
+}
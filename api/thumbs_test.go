@@ -0,0 +1,40 @@
+package api
+
+import "testing"
+
+func TestNegotiateFormat(t *testing.T) {
+	tests := []struct {
+		name   string
+		accept string
+		want   string
+	}{
+		{
+			name:   "prefers avif when advertised",
+			accept: "image/avif,image/webp,image/*,*/*",
+			want:   "avif",
+		},
+		{
+			name:   "falls back to webp without avif",
+			accept: "image/webp,image/*,*/*",
+			want:   "webp",
+		},
+		{
+			name:   "falls back to jpeg for anything else",
+			accept: "text/html,application/xhtml+xml",
+			want:   "jpeg",
+		},
+		{
+			name:   "falls back to jpeg for an empty header",
+			accept: "",
+			want:   "jpeg",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := negotiateFormat(tt.accept); got != tt.want {
+				t.Errorf("negotiateFormat(%q) = %q, want %q", tt.accept, got, tt.want)
+			}
+		})
+	}
+}
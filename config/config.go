@@ -0,0 +1,57 @@
+package config
+
+import (
+	"gopkg.in/yaml.v3"
+	"os"
+)
+
+const (
+	ModeEager    = "eager"
+	ModeOnDemand = "on_demand"
+	ModeRejected = "rejected"
+)
+
+type (
+	Config struct {
+		Thumbs     Thumbs     `yaml:"thumbs"`
+		Validation Validation `yaml:"validation"`
+	}
+
+	Thumbs struct {
+		Mode       string   `yaml:"mode"`
+		Dimensions []int    `yaml:"dimensions"`
+		Formats    []string `yaml:"formats"`
+	}
+
+	// Validation bounds what the worker will decode: a format allowlist, a
+	// maximum upload size and a maximum decoded pixel count, to reject
+	// decompression bombs before they're fully decoded.
+	Validation struct {
+		AllowedFormats []string `yaml:"allowed_formats"`
+		MaxPixels      int64    `yaml:"max_pixels"`
+		MaxFileSize    int64    `yaml:"max_file_size"`
+	}
+)
+
+func Load(filename string) (Config, error) {
+	var c Config
+	if filename == "" {
+		return c, nil
+	}
+
+	f, err := os.Open(filename)
+	if err != nil {
+		return c, err
+	}
+	defer f.Close()
+
+	if err := yaml.NewDecoder(f).Decode(&c); err != nil {
+		return c, err
+	}
+
+	return c, nil
+}
+
+func (t Thumbs) IsOnDemand() bool {
+	return t.Mode == ModeOnDemand
+}
@@ -0,0 +1,88 @@
+package consumer
+
+import (
+	"context"
+	"github.com/alancesar/photo-gallery/thumbs/pubsub"
+	"github.com/streadway/amqp"
+	"time"
+)
+
+type (
+	ThumbsWorker interface {
+		Process(ctx context.Context, filename string) error
+	}
+
+	Consumer struct {
+		worker      ThumbsWorker
+		channel     *amqp.Channel
+		queue       string
+		retryPolicy pubsub.RetryPolicy
+	}
+)
+
+func NewConsumer(worker ThumbsWorker, channel *amqp.Channel, queue string, retryPolicy pubsub.RetryPolicy) *Consumer {
+	return &Consumer{
+		worker:      worker,
+		channel:     channel,
+		queue:       queue,
+		retryPolicy: retryPolicy,
+	}
+}
+
+func (c *Consumer) Consume(delivery amqp.Delivery) error {
+	if err := c.worker.Process(context.Background(), string(delivery.Body)); err != nil {
+		return c.handleFailure(delivery)
+	}
+
+	pubsub.MessagesTotal.WithLabelValues(pubsub.ResultOK).Inc()
+	return delivery.Ack(false)
+}
+
+// handleFailure either redelivers the message with an incremented
+// x-delivery-count header, or, once the retry policy is exhausted,
+// rejects it without requeueing so the broker routes it to the dead-letter
+// exchange declared alongside the queue.
+func (c *Consumer) handleFailure(delivery amqp.Delivery) error {
+	attempt := deliveryCount(delivery) + 1
+
+	if c.retryPolicy.Exhausted(attempt) {
+		pubsub.MessagesTotal.WithLabelValues(pubsub.ResultDead).Inc()
+		return delivery.Reject(false)
+	}
+
+	time.Sleep(c.retryPolicy.Backoff(attempt))
+
+	headers := delivery.Headers
+	if headers == nil {
+		headers = amqp.Table{}
+	}
+	headers[pubsub.DeliveryCountHeader] = int32(attempt)
+
+	if err := c.channel.Publish("", c.queue, false, false, amqp.Publishing{
+		ContentType: delivery.ContentType,
+		Body:        delivery.Body,
+		Headers:     headers,
+	}); err != nil {
+		return err
+	}
+
+	pubsub.MessagesTotal.WithLabelValues(pubsub.ResultRetry).Inc()
+	return delivery.Ack(false)
+}
+
+func deliveryCount(delivery amqp.Delivery) int {
+	if delivery.Headers == nil {
+		return 0
+	}
+
+	switch v := delivery.Headers[pubsub.DeliveryCountHeader].(type) {
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	case int:
+		return v
+	default:
+		return 0
+	}
+}
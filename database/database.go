@@ -0,0 +1,19 @@
+package database
+
+import (
+	"database/sql"
+	_ "github.com/lib/pq"
+)
+
+func NewConnection(dsn string) (*sql.DB, error) {
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Ping(); err != nil {
+		return nil, err
+	}
+
+	return db, nil
+}
@@ -0,0 +1,23 @@
+package thumb
+
+import (
+	"fmt"
+	"io"
+)
+
+type (
+	Thumbnail struct {
+		Filename string
+		Height   int
+		Quality  int
+		Format   string
+		Reader   io.Reader
+	}
+)
+
+// Key returns the thumbnail's storage key, derived from the original
+// filename plus its rendered height, quality and format so every
+// dimension/encoding combination gets its own object under "preview/".
+func Key(filename string, height, quality int, format string) string {
+	return fmt.Sprintf("preview/%s_h%dq%d.%s", filename, height, quality, format)
+}
@@ -0,0 +1,39 @@
+package thumb
+
+import "testing"
+
+func TestKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		filename string
+		height   int
+		quality  int
+		format   string
+		want     string
+	}{
+		{
+			name:     "jpeg",
+			filename: "photo.jpg",
+			height:   200,
+			quality:  80,
+			format:   "jpeg",
+			want:     "preview/photo.jpg_h200q80.jpeg",
+		},
+		{
+			name:     "webp at a different height and quality",
+			filename: "vacation.png",
+			height:   480,
+			quality:  60,
+			format:   "webp",
+			want:     "preview/vacation.png_h480q60.webp",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Key(tt.filename, tt.height, tt.quality, tt.format); got != tt.want {
+				t.Errorf("Key() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
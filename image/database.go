@@ -0,0 +1,96 @@
+package image
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"github.com/alancesar/photo-gallery/thumbs/config"
+)
+
+var (
+	ErrPhotoNotFound    = errors.New("photo not found")
+	ErrMetadataNotFound = errors.New("metadata not found")
+)
+
+type (
+	Photo struct {
+		Filename string
+		Mode     string
+		Reason   string
+	}
+
+	Database struct {
+		conn *sql.DB
+	}
+)
+
+func NewDatabase(conn *sql.DB) *Database {
+	return &Database{
+		conn: conn,
+	}
+}
+
+func (d *Database) Save(ctx context.Context, photo Photo) error {
+	_, err := d.conn.ExecContext(ctx, `
+		INSERT INTO photos (filename, mode, reason) VALUES ($1, $2, $3)
+		ON CONFLICT (filename) DO UPDATE SET mode = excluded.mode, reason = excluded.reason`,
+		photo.Filename, photo.Mode, photo.Reason)
+	return err
+}
+
+// SaveRejection records why a photo was turned away during validation,
+// instead of the worker crashing the consumer over a malformed upload.
+func (d *Database) SaveRejection(ctx context.Context, filename, reason string) error {
+	return d.Save(ctx, Photo{
+		Filename: filename,
+		Mode:     config.ModeRejected,
+		Reason:   reason,
+	})
+}
+
+func (d *Database) FindByFilename(ctx context.Context, filename string) (Photo, error) {
+	var photo Photo
+	row := d.conn.QueryRowContext(ctx, `SELECT filename, mode, reason FROM photos WHERE filename = $1`, filename)
+	if err := row.Scan(&photo.Filename, &photo.Mode, &photo.Reason); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Photo{}, ErrPhotoNotFound
+		}
+
+		return Photo{}, err
+	}
+
+	return photo, nil
+}
+
+func (d *Database) SaveMetadata(ctx context.Context, metadata Metadata) error {
+	encoded, err := json.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+
+	_, err = d.conn.ExecContext(ctx, `
+		INSERT INTO photo_metadata (filename, data) VALUES ($1, $2)
+		ON CONFLICT (filename) DO UPDATE SET data = excluded.data`,
+		metadata.Filename, encoded)
+	return err
+}
+
+func (d *Database) FindMetadata(ctx context.Context, filename string) (Metadata, error) {
+	var encoded []byte
+	row := d.conn.QueryRowContext(ctx, `SELECT data FROM photo_metadata WHERE filename = $1`, filename)
+	if err := row.Scan(&encoded); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Metadata{}, ErrMetadataNotFound
+		}
+
+		return Metadata{}, err
+	}
+
+	var metadata Metadata
+	if err := json.Unmarshal(encoded, &metadata); err != nil {
+		return Metadata{}, err
+	}
+
+	return metadata, nil
+}
@@ -0,0 +1,159 @@
+package image
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/disintegration/imaging"
+	"github.com/rwcarlsen/goexif/exif"
+	"image"
+	"io"
+	"time"
+)
+
+type (
+	GPSCoordinates struct {
+		Latitude  float64 `json:"latitude"`
+		Longitude float64 `json:"longitude"`
+	}
+
+	Metadata struct {
+		Filename      string          `json:"filename"`
+		Width         int             `json:"width"`
+		Height        int             `json:"height"`
+		DateTaken     time.Time       `json:"date_taken,omitempty"`
+		Camera        string          `json:"camera,omitempty"`
+		Orientation   int             `json:"orientation,omitempty"`
+		GPS           *GPSCoordinates `json:"gps,omitempty"`
+		DominantColor string          `json:"dominant_color"`
+	}
+
+	MetadataExtractor struct {
+	}
+)
+
+func NewMetadataExtractor() *MetadataExtractor {
+	return &MetadataExtractor{}
+}
+
+// Extract reads EXIF, dominant color and true pixel dimensions out of the
+// photo. reader must support multiple reads, so callers typically pass a
+// bytes.Reader obtained by buffering the original once.
+func (e *MetadataExtractor) Extract(reader io.ReadSeeker, filename string) (Metadata, error) {
+	metadata := Metadata{Filename: filename}
+
+	if x, err := exif.Decode(reader); err == nil {
+		if dateTaken, err := x.DateTime(); err == nil {
+			metadata.DateTaken = dateTaken
+		}
+
+		if camera, err := x.Get(exif.Model); err == nil {
+			if value, err := camera.StringVal(); err == nil {
+				metadata.Camera = value
+			}
+		}
+
+		if orientation, err := x.Get(exif.Orientation); err == nil {
+			if value, err := orientation.Int(0); err == nil {
+				metadata.Orientation = value
+			}
+		}
+
+		if lat, long, err := x.LatLong(); err == nil {
+			metadata.GPS = &GPSCoordinates{Latitude: lat, Longitude: long}
+		}
+	}
+
+	if _, err := reader.Seek(0, io.SeekStart); err != nil {
+		return Metadata{}, err
+	}
+
+	src, err := imaging.Decode(reader, imaging.AutoOrientation(true))
+	if err != nil {
+		return Metadata{}, err
+	}
+
+	bounds := src.Bounds()
+	metadata.Width = bounds.Dx()
+	metadata.Height = bounds.Dy()
+	metadata.DominantColor = dominantColor(src)
+
+	return metadata, nil
+}
+
+// dominantColor approximates the image's dominant color as the average of
+// every pixel, returned as a "#rrggbb" hex string.
+func dominantColor(img image.Image) string {
+	bounds := img.Bounds()
+	var r, g, b, count uint64
+
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			pr, pg, pb, _ := img.At(x, y).RGBA()
+			r += uint64(pr >> 8)
+			g += uint64(pg >> 8)
+			b += uint64(pb >> 8)
+			count++
+		}
+	}
+
+	if count == 0 {
+		return "#000000"
+	}
+
+	return hexColor(byte(r/count), byte(g/count), byte(b/count))
+}
+
+func hexColor(r, g, b byte) string {
+	const hextable = "0123456789abcdef"
+	buf := make([]byte, 7)
+	buf[0] = '#'
+	buf[1], buf[2] = hextable[r>>4], hextable[r&0xf]
+	buf[3], buf[4] = hextable[g>>4], hextable[g&0xf]
+	buf[5], buf[6] = hextable[b>>4], hextable[b&0xf]
+	return string(buf)
+}
+
+// Buffer fully reads r so it can be passed to Extract, which needs to seek
+// back to the start after EXIF decoding.
+func Buffer(r io.Reader) (*bytes.Reader, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewReader(data), nil
+}
+
+// Sidecars renders the metadata as the small "photometadata/<filename>/*"
+// objects consumed by clients that don't want to fetch the full JSON blob.
+func (m Metadata) Sidecars() (map[string][]byte, error) {
+	exifBytes, err := json.Marshal(struct {
+		Camera      string          `json:"camera,omitempty"`
+		Orientation int             `json:"orientation,omitempty"`
+		GPS         *GPSCoordinates `json:"gps,omitempty"`
+	}{m.Camera, m.Orientation, m.GPS})
+	if err != nil {
+		return nil, err
+	}
+
+	size, err := json.Marshal(struct {
+		Width  int `json:"width"`
+		Height int `json:"height"`
+	}{m.Width, m.Height})
+	if err != nil {
+		return nil, err
+	}
+
+	date := ""
+	if !m.DateTaken.IsZero() {
+		date = m.DateTaken.Format(time.RFC3339)
+	}
+
+	return map[string][]byte{
+		"size":  size,
+		"date":  []byte(date),
+		"title": []byte(m.Filename),
+		"tags":  []byte("[]"),
+		"exif":  exifBytes,
+	}, nil
+}
@@ -0,0 +1,104 @@
+package image
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/alancesar/photo-gallery/thumbs/domain/thumb"
+	"github.com/chai2010/webp"
+	"github.com/disintegration/imaging"
+	"image"
+	"io"
+	"sync"
+)
+
+// DefaultQuality is the quality eager thumbnails are rendered and keyed
+// at; api.GetThumbsHandler must presign eager-mode objects using this
+// same value rather than the client-supplied quality, since that's the
+// only quality the worker ever writes.
+const DefaultQuality = 80
+
+var defaultFormats = []string{"jpeg", "webp"}
+
+type (
+	ImagingProcessor struct {
+	}
+
+	renderJob struct {
+		height int
+		format string
+	}
+)
+
+func NewImagingProcessor() *ImagingProcessor {
+	return &ImagingProcessor{}
+}
+
+// Process decodes the original once, then renders every dimension in
+// every requested encoding (JPEG baseline, WebP and, when asked for,
+// AVIF) concurrently.
+func (p *ImagingProcessor) Process(reader io.Reader, filename string, dimensions []int, formats []string) ([]thumb.Thumbnail, error) {
+	src, err := imaging.Decode(reader, imaging.AutoOrientation(true))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(formats) == 0 {
+		formats = defaultFormats
+	}
+
+	jobs := make([]renderJob, 0, len(dimensions)*len(formats))
+	for _, height := range dimensions {
+		for _, format := range formats {
+			jobs = append(jobs, renderJob{height: height, format: format})
+		}
+	}
+
+	thumbnails := make([]thumb.Thumbnail, len(jobs))
+	errs := make([]error, len(jobs))
+
+	var wg sync.WaitGroup
+	for i, job := range jobs {
+		wg.Add(1)
+		go func(i int, job renderJob) {
+			defer wg.Done()
+
+			resized := imaging.Resize(src, 0, job.height, imaging.Lanczos)
+
+			var buf bytes.Buffer
+			if err := encode(&buf, resized, job.format, DefaultQuality); err != nil {
+				errs[i] = err
+				return
+			}
+
+			thumbnails[i] = thumb.Thumbnail{
+				Filename: thumb.Key(filename, job.height, DefaultQuality, job.format),
+				Height:   job.height,
+				Quality:  DefaultQuality,
+				Format:   job.format,
+				Reader:   &buf,
+			}
+		}(i, job)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return thumbnails, nil
+}
+
+func encode(w io.Writer, img image.Image, format string, quality int) error {
+	switch format {
+	case "jpeg":
+		return imaging.Encode(w, img, imaging.JPEG, imaging.JPEGQuality(quality))
+	case "webp":
+		return webp.Encode(w, img, &webp.Options{Quality: float32(quality)})
+	case "avif":
+		return encodeAVIF(w, img, quality)
+	default:
+		return fmt.Errorf("unsupported thumbnail format %q", format)
+	}
+}
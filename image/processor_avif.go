@@ -0,0 +1,16 @@
+//go:build avif
+
+package image
+
+import (
+	avif "github.com/kagami/go-avif"
+	"image"
+	"io"
+)
+
+// encodeAVIF is a cgo wrapper around libaom and needs aom/aom_encoder.h
+// at compile time, so it's opt-in per environment via the "avif" build
+// tag rather than an unconditional dependency of every build.
+func encodeAVIF(w io.Writer, img image.Image, quality int) error {
+	return avif.Encode(w, img, &avif.Options{Quality: quality})
+}
@@ -0,0 +1,15 @@
+//go:build !avif
+
+package image
+
+import (
+	"fmt"
+	"image"
+	"io"
+)
+
+// encodeAVIF stubs out AVIF encoding for builds without the "avif" tag,
+// so "jpeg" and "webp" thumbnails don't need libaom to be installed.
+func encodeAVIF(_ io.Writer, _ image.Image, _ int) error {
+	return fmt.Errorf("avif encoding requires building with the \"avif\" build tag")
+}
@@ -0,0 +1,34 @@
+package image
+
+import (
+	"encoding/json"
+	"github.com/alancesar/photo-gallery/thumbs/pubsub"
+)
+
+type (
+	Event struct {
+		Filename string    `json:"filename"`
+		Mode     string    `json:"mode"`
+		Metadata *Metadata `json:"metadata,omitempty"`
+		Error    string    `json:"error,omitempty"`
+	}
+
+	Producer struct {
+		publisher pubsub.Publisher
+	}
+)
+
+func NewProducer(publisher pubsub.Publisher) *Producer {
+	return &Producer{
+		publisher: publisher,
+	}
+}
+
+func (p *Producer) Publish(event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return p.publisher.Publish(body)
+}
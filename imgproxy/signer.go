@@ -0,0 +1,52 @@
+package imgproxy
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+type (
+	Signer struct {
+		baseURL string
+		key     []byte
+		salt    []byte
+	}
+)
+
+func NewSigner(baseURL, key, salt string) (*Signer, error) {
+	decodedKey, err := hex.DecodeString(key)
+	if err != nil {
+		return nil, fmt.Errorf("decode imgproxy key: %w", err)
+	}
+
+	decodedSalt, err := hex.DecodeString(salt)
+	if err != nil {
+		return nil, fmt.Errorf("decode imgproxy salt: %w", err)
+	}
+
+	return &Signer{
+		baseURL: baseURL,
+		key:     decodedKey,
+		salt:    decodedSalt,
+	}, nil
+}
+
+// SignedURL builds a signed imgproxy URL for the given processing path, e.g.
+// "/resize:fit:200:200/quality:80/plain/s3://bucket/filename@jpg".
+func (s *Signer) SignedURL(processingPath string) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write(s.salt)
+	mac.Write([]byte(processingPath))
+	signature := base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+
+	return fmt.Sprintf("%s/%s%s", s.baseURL, signature, processingPath)
+}
+
+// ProcessingPath builds the imgproxy processing path for a resize of an
+// object stored at s3://bucket/key into the given height, quality and format.
+func ProcessingPath(bucket, key string, height, quality int, format string) string {
+	return fmt.Sprintf("/resize:fit:0:%d/quality:%d/plain/s3://%s/%s@%s", height, quality, bucket, key, format)
+}
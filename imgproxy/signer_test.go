@@ -0,0 +1,42 @@
+package imgproxy
+
+import "testing"
+
+func TestProcessingPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		bucket  string
+		key     string
+		height  int
+		quality int
+		format  string
+		want    string
+	}{
+		{
+			name:    "jpeg",
+			bucket:  "photos",
+			key:     "photo.jpg",
+			height:  200,
+			quality: 80,
+			format:  "jpeg",
+			want:    "/resize:fit:0:200/quality:80/plain/s3://photos/photo.jpg@jpeg",
+		},
+		{
+			name:    "webp at a different height and quality",
+			bucket:  "other-bucket",
+			key:     "nested/vacation.png",
+			height:  480,
+			quality: 60,
+			format:  "webp",
+			want:    "/resize:fit:0:480/quality:60/plain/s3://other-bucket/nested/vacation.png@webp",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ProcessingPath(tt.bucket, tt.key, tt.height, tt.quality, tt.format); got != tt.want {
+				t.Errorf("ProcessingPath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
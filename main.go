@@ -1,6 +1,7 @@
 package main
 
 import (
+	gcs "cloud.google.com/go/storage"
 	"context"
 	"fmt"
 	"github.com/alancesar/photo-gallery/thumbs/api"
@@ -8,6 +9,7 @@ import (
 	"github.com/alancesar/photo-gallery/thumbs/consumer"
 	"github.com/alancesar/photo-gallery/thumbs/database"
 	"github.com/alancesar/photo-gallery/thumbs/image"
+	"github.com/alancesar/photo-gallery/thumbs/imgproxy"
 	"github.com/alancesar/photo-gallery/thumbs/pubsub"
 	"github.com/alancesar/photo-gallery/thumbs/storage"
 	"github.com/alancesar/photo-gallery/thumbs/worker"
@@ -16,11 +18,14 @@ import (
 	_ "github.com/joho/godotenv/autoload"
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/streadway/amqp"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
+	"time"
 )
 
 const (
@@ -39,7 +44,22 @@ const (
 	bucketExchangeNameEnv = "BUCKET_EXCHANGE_NAME"
 	workerExchangeNameEnv = "WORKER_EXCHANGE_NAME"
 	configFileEnv         = "CONFIG_FILE"
+	imgproxyURLEnv        = "IMGPROXY_URL"
+	imgproxyKeyEnv        = "IMGPROXY_KEY"
+	imgproxySaltEnv       = "IMGPROXY_SALT"
+	imgproxyProxyModeEnv  = "IMGPROXY_PROXY_MODE"
+	storageBackendEnv     = "STORAGE_BACKEND"
+	fsBaseDirEnv          = "FS_BASE_DIR"
 	fanoutExchangeKind    = "fanout"
+	dlxSuffix             = ".dlx"
+	parkingQueueSuffix    = ".parking"
+
+	storageBackendGCS = "gcs"
+	storageBackendFS  = "fs"
+
+	retryMaxAttempts       = 5
+	retryBaseBackoff       = 500 * time.Millisecond
+	retryExponentialFactor = 2.0
 )
 
 func main() {
@@ -56,10 +76,21 @@ func main() {
 		log.Fatalln(err)
 	}
 
-	client, err := minio.New(os.Getenv(minioEndpointEnv), &minio.Options{
-		Creds:  credentials.NewStaticV4(os.Getenv(minioRootUserEnv), os.Getenv(minioRootPasswordEnv), ""),
-		Secure: false,
-	})
+	backend := os.Getenv(storageBackendEnv)
+
+	var minioClient *minio.Client
+	var gcsClient *gcs.Client
+	switch backend {
+	case storageBackendGCS:
+		gcsClient, err = gcs.NewClient(context.Background())
+	case storageBackendFS:
+		// no client needed, objects are plain files under fsBaseDirEnv
+	default:
+		minioClient, err = minio.New(os.Getenv(minioEndpointEnv), &minio.Options{
+			Creds:  credentials.NewStaticV4(os.Getenv(minioRootUserEnv), os.Getenv(minioRootPasswordEnv), ""),
+			Secure: false,
+		})
+	}
 	if err != nil {
 		log.Fatalln(err)
 	}
@@ -88,24 +119,47 @@ func main() {
 		log.Fatalln(err)
 	}
 
-	queue, err := declareAndBindQueue(channel, os.Getenv(queueNameEnv), os.Getenv(bucketExchangeNameEnv))
+	dlxName := os.Getenv(bucketExchangeNameEnv) + dlxSuffix
+	if err := declareDeadLetterExchange(channel, dlxName, os.Getenv(queueNameEnv)+parkingQueueSuffix); err != nil {
+		log.Fatalln(err)
+	}
+
+	queue, err := declareAndBindQueue(channel, os.Getenv(queueNameEnv), os.Getenv(bucketExchangeNameEnv), dlxName)
 	if err != nil {
 		log.Fatalln(err)
 	}
 
+	photoStorage := newObjectStore(backend, minioClient, gcsClient, os.Getenv(photosBucketEnv))
+	thumbStorage := newObjectStore(backend, minioClient, gcsClient, os.Getenv(thumbsBucketEnv))
+
 	db := image.NewDatabase(dbConnection)
 	dimensions := configs.Thumbs.Dimensions
 	publisher := pubsub.NewAmpqPublisher(channel, os.Getenv(workerExchangeNameEnv))
 	bundle := worker.Bundle{
-		PhotoStorage: storage.NewMinioStorage(client, os.Getenv(photosBucketEnv)),
-		ThumbStorage: storage.NewMinioStorage(client, os.Getenv(thumbsBucketEnv)),
-		Database:     db,
-		Processor:    image.NewImagingProcessor(),
-		Producer:     image.NewProducer(publisher),
-		Dimensions:   dimensions,
+		PhotoStorage:      photoStorage,
+		ThumbStorage:      thumbStorage,
+		Database:          db,
+		Validator:         image.NewValidator(configs.Validation.AllowedFormats, configs.Validation.MaxPixels, configs.Validation.MaxFileSize),
+		Processor:         image.NewImagingProcessor(),
+		MetadataExtractor: image.NewMetadataExtractor(),
+		Producer:          image.NewProducer(publisher),
+		Dimensions:        dimensions,
+		Formats:           configs.Thumbs.Formats,
+		Mode:              configs.Thumbs.Mode,
 	}
 	w := worker.NewThumbsWorker(bundle)
-	c := consumer.NewConsumer(w)
+	retryPolicy := pubsub.RetryPolicy{
+		MaxAttempts:       retryMaxAttempts,
+		BaseBackoff:       retryBaseBackoff,
+		ExponentialFactor: retryExponentialFactor,
+	}
+	c := consumer.NewConsumer(w, channel, queue.Name, retryPolicy)
+
+	signer, err := imgproxy.NewSigner(os.Getenv(imgproxyURLEnv), os.Getenv(imgproxyKeyEnv), os.Getenv(imgproxySaltEnv))
+	if err != nil {
+		log.Fatalln(err)
+	}
+	imgproxyProxyMode := os.Getenv(imgproxyProxyModeEnv) == "true"
 
 	signals := make(chan os.Signal, 1)
 	signal.Notify(signals, os.Interrupt)
@@ -122,7 +176,10 @@ func main() {
 	go func() {
 		engine := gin.Default()
 		engine.Use(cors.Default())
-		engine.Handle(http.MethodGet, "/api/thumbs/:filename", api.GetThumbsHandler(db))
+		engine.Handle(http.MethodGet, "/api/thumbs/:filename/h:height/q:quality.:format",
+			api.GetThumbsHandler(db, thumbStorage, signer, os.Getenv(photosBucketEnv), imgproxyProxyMode))
+		engine.Handle(http.MethodGet, "/api/photos/:filename/metadata", api.GetPhotoMetadataHandler(db))
+		engine.Handle(http.MethodGet, "/metrics", gin.WrapH(promhttp.Handler()))
 		if err := engine.Run(":8082"); err != nil {
 			log.Fatalln(err)
 		}
@@ -151,18 +208,49 @@ func declareExchange(channel *amqp.Channel, exchangeName string) error {
 	)
 }
 
-func declareAndBindQueue(channel *amqp.Channel, queue, exchange string) (amqp.Queue, error) {
+func declareAndBindQueue(channel *amqp.Channel, queue, exchange, dlxName string) (amqp.Queue, error) {
 	q, err := channel.QueueDeclare(
 		queue,
 		true,
 		false,
 		false,
 		false,
-		nil,
+		amqp.Table{
+			"x-dead-letter-exchange": dlxName,
+		},
 	)
 	if err != nil {
 		return amqp.Queue{}, err
 	}
 
 	return q, channel.QueueBind(q.Name, "", exchange, false, nil)
+}
+
+// newObjectStore picks the storage.ObjectStore implementation selected by
+// STORAGE_BACKEND; it defaults to MinIO to preserve prior behavior.
+func newObjectStore(backend string, minioClient *minio.Client, gcsClient *gcs.Client, bucket string) storage.ObjectStore {
+	switch backend {
+	case storageBackendGCS:
+		return storage.NewGCSStore(gcsClient.Bucket(bucket))
+	case storageBackendFS:
+		return storage.NewFSStore(filepath.Join(os.Getenv(fsBaseDirEnv), bucket))
+	default:
+		return storage.NewMinioStore(minioClient, bucket)
+	}
+}
+
+// declareDeadLetterExchange declares the exchange poison messages are
+// routed to once the consumer's retry policy is exhausted, along with a
+// parking queue so they are retained for inspection instead of discarded.
+func declareDeadLetterExchange(channel *amqp.Channel, dlxName, parkingQueueName string) error {
+	if err := channel.ExchangeDeclare(dlxName, fanoutExchangeKind, true, false, false, false, nil); err != nil {
+		return err
+	}
+
+	parkingQueue, err := channel.QueueDeclare(parkingQueueName, true, false, false, false, nil)
+	if err != nil {
+		return err
+	}
+
+	return channel.QueueBind(parkingQueue.Name, "", dlxName, false, nil)
 }
\ No newline at end of file
@@ -0,0 +1,19 @@
+package pubsub
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// MessagesTotal counts processed deliveries by outcome so operators can
+// spot a consumer stuck redelivering poison messages.
+var MessagesTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "thumbs_messages_total",
+	Help: "Total number of AMQP deliveries processed by the thumbs worker, by result.",
+}, []string{"result"})
+
+const (
+	ResultOK    = "ok"
+	ResultRetry = "retry"
+	ResultDead  = "dead"
+)
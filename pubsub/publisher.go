@@ -0,0 +1,30 @@
+package pubsub
+
+import (
+	"github.com/streadway/amqp"
+)
+
+type (
+	Publisher interface {
+		Publish(body []byte) error
+	}
+
+	AmqpPublisher struct {
+		channel  *amqp.Channel
+		exchange string
+	}
+)
+
+func NewAmpqPublisher(channel *amqp.Channel, exchange string) *AmqpPublisher {
+	return &AmqpPublisher{
+		channel:  channel,
+		exchange: exchange,
+	}
+}
+
+func (p *AmqpPublisher) Publish(body []byte) error {
+	return p.channel.Publish(p.exchange, "", false, false, amqp.Publishing{
+		ContentType: "application/json",
+		Body:        body,
+	})
+}
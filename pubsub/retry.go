@@ -0,0 +1,34 @@
+package pubsub
+
+import (
+	"math"
+	"time"
+)
+
+const DeliveryCountHeader = "x-delivery-count"
+
+type (
+	// RetryPolicy bounds how many times a failed message is redelivered
+	// before it is routed to the dead-letter exchange.
+	RetryPolicy struct {
+		MaxAttempts       int
+		BaseBackoff       time.Duration
+		ExponentialFactor float64
+	}
+)
+
+// Backoff returns how long to wait before redelivering the attempt-th
+// retry (1-indexed).
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+
+	factor := math.Pow(p.ExponentialFactor, float64(attempt-1))
+	return time.Duration(float64(p.BaseBackoff) * factor)
+}
+
+// Exhausted reports whether attempt has used up the policy's budget.
+func (p RetryPolicy) Exhausted(attempt int) bool {
+	return attempt >= p.MaxAttempts
+}
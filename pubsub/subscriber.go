@@ -0,0 +1,47 @@
+package pubsub
+
+import (
+	"context"
+	"github.com/streadway/amqp"
+	"log"
+)
+
+type (
+	Consumer interface {
+		Consume(delivery amqp.Delivery) error
+	}
+
+	AmqpSubscriber struct {
+		channel *amqp.Channel
+		queue   amqp.Queue
+	}
+)
+
+func NewAmqpSubscriber(channel *amqp.Channel, queue amqp.Queue) *AmqpSubscriber {
+	return &AmqpSubscriber{
+		channel: channel,
+		queue:   queue,
+	}
+}
+
+func (s *AmqpSubscriber) Subscribe(ctx context.Context, consumer Consumer) error {
+	deliveries, err := s.channel.Consume(s.queue.Name, "", false, false, false, false, nil)
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case delivery := <-deliveries:
+			// Consumer.Consume owns the delivery's ack/reject/requeue
+			// entirely (it needs to republish with an incremented
+			// x-delivery-count on retry), so the subscriber must not
+			// also ack or nack here.
+			if err := consumer.Consume(delivery); err != nil {
+				log.Println(err)
+			}
+		}
+	}
+}
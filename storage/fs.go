@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+type (
+	// fsStore keeps objects as plain files under a base directory, for
+	// local development and tests where running MinIO or GCS isn't worth it.
+	fsStore struct {
+		baseDir string
+	}
+)
+
+func NewFSStore(baseDir string) ObjectStore {
+	return &fsStore{
+		baseDir: baseDir,
+	}
+}
+
+func (s *fsStore) path(key string) string {
+	return filepath.Join(s.baseDir, filepath.FromSlash(key))
+}
+
+func (s *fsStore) Put(_ context.Context, key string, reader io.Reader, _ string) error {
+	path := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, reader)
+	return err
+}
+
+func (s *fsStore) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	f, err := os.Open(s.path(key))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// PresignGet has no notion of signing on the filesystem; it returns a plain
+// file:// URI to the object. ttl is ignored, so the URI does not expire.
+func (s *fsStore) PresignGet(_ context.Context, key string, _ time.Duration) (string, error) {
+	return fmt.Sprintf("file://%s", s.path(key)), nil
+}
+
+func (s *fsStore) Stat(_ context.Context, key string) (ObjectInfo, error) {
+	info, err := os.Stat(s.path(key))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ObjectInfo{}, ErrNotFound
+		}
+
+		return ObjectInfo{}, err
+	}
+
+	return ObjectInfo{
+		Key:  key,
+		Size: info.Size(),
+	}, nil
+}
+
+func (s *fsStore) Delete(_ context.Context, key string) error {
+	if err := os.Remove(s.path(key)); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return ErrNotFound
+		}
+
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package storage
+
+import (
+	gcs "cloud.google.com/go/storage"
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+type (
+	gcsStore struct {
+		handle *gcs.BucketHandle
+	}
+)
+
+// NewGCSStore wraps a GCS bucket handle behind the common ObjectStore
+// interface.
+func NewGCSStore(handle *gcs.BucketHandle) ObjectStore {
+	return &gcsStore{
+		handle: handle,
+	}
+}
+
+func (s *gcsStore) Put(ctx context.Context, key string, reader io.Reader, contentType string) error {
+	writer := s.handle.Object(key).NewWriter(ctx)
+	writer.ContentType = contentType
+
+	if _, err := io.Copy(writer, reader); err != nil {
+		return err
+	}
+
+	return writer.Close()
+}
+
+func (s *gcsStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	reader, err := s.handle.Object(key).NewReader(ctx)
+	if err != nil {
+		if errors.Is(err, gcs.ErrObjectNotExist) {
+			return nil, ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	return reader, nil
+}
+
+func (s *gcsStore) PresignGet(_ context.Context, key string, ttl time.Duration) (string, error) {
+	return s.handle.SignedURL(key, &gcs.SignedURLOptions{
+		Method:  "GET",
+		Expires: time.Now().Add(ttl),
+	})
+}
+
+func (s *gcsStore) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	attrs, err := s.handle.Object(key).Attrs(ctx)
+	if err != nil {
+		if errors.Is(err, gcs.ErrObjectNotExist) {
+			return ObjectInfo{}, ErrNotFound
+		}
+
+		return ObjectInfo{}, err
+	}
+
+	return ObjectInfo{
+		Key:         key,
+		Size:        attrs.Size,
+		ContentType: attrs.ContentType,
+	}, nil
+}
+
+func (s *gcsStore) Delete(ctx context.Context, key string) error {
+	if err := s.handle.Object(key).Delete(ctx); err != nil {
+		if errors.Is(err, gcs.ErrObjectNotExist) {
+			return ErrNotFound
+		}
+
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"github.com/minio/minio-go/v7"
+	"io"
+	"time"
+)
+
+type (
+	minioStore struct {
+		client *minio.Client
+		bucket string
+	}
+)
+
+func NewMinioStore(client *minio.Client, bucket string) ObjectStore {
+	return &minioStore{
+		client: client,
+		bucket: bucket,
+	}
+}
+
+func (s *minioStore) Put(ctx context.Context, key string, reader io.Reader, contentType string) error {
+	_, err := s.client.PutObject(ctx, s.bucket, key, reader, -1, minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	return err
+}
+
+func (s *minioStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	object, err := s.client.GetObject(ctx, s.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := object.Stat(); err != nil {
+		var errResponse minio.ErrorResponse
+		if errors.As(err, &errResponse) && errResponse.Code == "NoSuchKey" {
+			return nil, ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	return object, nil
+}
+
+func (s *minioStore) PresignGet(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	u, err := s.client.PresignedGetObject(ctx, s.bucket, key, ttl, nil)
+	if err != nil {
+		return "", err
+	}
+
+	return u.String(), nil
+}
+
+func (s *minioStore) Stat(ctx context.Context, key string) (ObjectInfo, error) {
+	info, err := s.client.StatObject(ctx, s.bucket, key, minio.StatObjectOptions{})
+	if err != nil {
+		var errResponse minio.ErrorResponse
+		if errors.As(err, &errResponse) && errResponse.Code == "NoSuchKey" {
+			return ObjectInfo{}, ErrNotFound
+		}
+
+		return ObjectInfo{}, err
+	}
+
+	return ObjectInfo{
+		Key:         key,
+		Size:        info.Size,
+		ContentType: info.ContentType,
+	}, nil
+}
+
+func (s *minioStore) Delete(ctx context.Context, key string) error {
+	return s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{})
+}
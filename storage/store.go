@@ -0,0 +1,29 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+var ErrNotFound = errors.New("object not found")
+
+type (
+	ObjectInfo struct {
+		Key         string
+		Size        int64
+		ContentType string
+	}
+
+	// ObjectStore is the storage abstraction every backend (GCS, MinIO,
+	// local filesystem) implements, so the rest of the service can be
+	// written against one shape regardless of where objects actually live.
+	ObjectStore interface {
+		Put(ctx context.Context, key string, reader io.Reader, contentType string) error
+		Get(ctx context.Context, key string) (io.ReadCloser, error)
+		PresignGet(ctx context.Context, key string, ttl time.Duration) (string, error)
+		Stat(ctx context.Context, key string) (ObjectInfo, error)
+		Delete(ctx context.Context, key string) error
+	}
+)
@@ -0,0 +1,194 @@
+package worker
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/alancesar/photo-gallery/thumbs/config"
+	"github.com/alancesar/photo-gallery/thumbs/domain/thumb"
+	"github.com/alancesar/photo-gallery/thumbs/image"
+	"github.com/alancesar/photo-gallery/thumbs/storage"
+	"io"
+)
+
+const metadataPrefix = "photometadata"
+
+type (
+	Processor interface {
+		Process(reader io.Reader, filename string, dimensions []int, formats []string) ([]thumb.Thumbnail, error)
+	}
+
+	MetadataExtractor interface {
+		Extract(reader io.ReadSeeker, filename string) (image.Metadata, error)
+	}
+
+	Validator interface {
+		Validate(size int64, reader io.Reader) error
+	}
+
+	Bundle struct {
+		PhotoStorage      storage.ObjectStore
+		ThumbStorage      storage.ObjectStore
+		Database          *image.Database
+		Validator         Validator
+		Processor         Processor
+		MetadataExtractor MetadataExtractor
+		Producer          *image.Producer
+		Dimensions        []int
+		Formats           []string
+		Mode              string
+	}
+
+	ThumbsWorker struct {
+		bundle Bundle
+	}
+)
+
+func NewThumbsWorker(bundle Bundle) *ThumbsWorker {
+	return &ThumbsWorker{
+		bundle: bundle,
+	}
+}
+
+func (w *ThumbsWorker) Process(ctx context.Context, filename string) error {
+	if err := w.validate(ctx, filename); err != nil {
+		var validationErr *image.ValidationError
+		if errors.As(err, &validationErr) {
+			return w.reject(ctx, filename, validationErr.Reason)
+		}
+
+		return err
+	}
+
+	metadata, err := w.extractMetadata(ctx, filename)
+	if err != nil {
+		return err
+	}
+
+	if w.bundle.Mode == config.ModeOnDemand {
+		return w.processOnDemand(ctx, filename, metadata)
+	}
+
+	return w.processEager(ctx, filename, metadata)
+}
+
+// validate sniffs the upload's magic bytes and header-decoded pixel count
+// before committing to a full decode, so a decompression bomb or
+// unsupported format never reaches the processor.
+func (w *ThumbsWorker) validate(ctx context.Context, filename string) error {
+	info, err := w.bundle.PhotoStorage.Stat(ctx, filename)
+	if err != nil {
+		return err
+	}
+
+	reader, err := w.bundle.PhotoStorage.Get(ctx, filename)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	return w.bundle.Validator.Validate(info.Size, reader)
+}
+
+// reject records why a photo was turned away and publishes a typed error
+// event instead of crashing the consumer over a malformed upload.
+func (w *ThumbsWorker) reject(ctx context.Context, filename, reason string) error {
+	if err := w.bundle.Database.SaveRejection(ctx, filename, reason); err != nil {
+		return err
+	}
+
+	return w.bundle.Producer.Publish(image.Event{
+		Filename: filename,
+		Mode:     config.ModeRejected,
+		Error:    reason,
+	})
+}
+
+// extractMetadata reads the original once, extracts EXIF/dominant
+// color/dimensions from it and persists both the row and the
+// "photometadata/<filename>/*" sidecar objects, regardless of mode.
+func (w *ThumbsWorker) extractMetadata(ctx context.Context, filename string) (image.Metadata, error) {
+	reader, err := w.bundle.PhotoStorage.Get(ctx, filename)
+	if err != nil {
+		return image.Metadata{}, err
+	}
+	defer reader.Close()
+
+	buffered, err := image.Buffer(reader)
+	if err != nil {
+		return image.Metadata{}, err
+	}
+
+	metadata, err := w.bundle.MetadataExtractor.Extract(buffered, filename)
+	if err != nil {
+		return image.Metadata{}, err
+	}
+
+	if err := w.bundle.Database.SaveMetadata(ctx, metadata); err != nil {
+		return image.Metadata{}, err
+	}
+
+	sidecars, err := metadata.Sidecars()
+	if err != nil {
+		return image.Metadata{}, err
+	}
+
+	for name, content := range sidecars {
+		key := fmt.Sprintf("%s/%s/%s", metadataPrefix, filename, name)
+		if err := w.bundle.ThumbStorage.Put(ctx, key, bytes.NewReader(content), "application/json"); err != nil {
+			return image.Metadata{}, err
+		}
+	}
+
+	return metadata, nil
+}
+
+// processOnDemand stores only the original's metadata, deferring thumbnail
+// rendering to imgproxy at request time.
+func (w *ThumbsWorker) processOnDemand(ctx context.Context, filename string, metadata image.Metadata) error {
+	if err := w.bundle.Database.Save(ctx, image.Photo{
+		Filename: filename,
+		Mode:     config.ModeOnDemand,
+	}); err != nil {
+		return err
+	}
+
+	return w.bundle.Producer.Publish(image.Event{
+		Filename: filename,
+		Mode:     config.ModeOnDemand,
+		Metadata: &metadata,
+	})
+}
+
+func (w *ThumbsWorker) processEager(ctx context.Context, filename string, metadata image.Metadata) error {
+	reader, err := w.bundle.PhotoStorage.Get(ctx, filename)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	thumbnails, err := w.bundle.Processor.Process(reader, filename, w.bundle.Dimensions, w.bundle.Formats)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range thumbnails {
+		if err := w.bundle.ThumbStorage.Put(ctx, t.Filename, t.Reader, "image/"+t.Format); err != nil {
+			return err
+		}
+	}
+
+	if err := w.bundle.Database.Save(ctx, image.Photo{
+		Filename: filename,
+		Mode:     config.ModeEager,
+	}); err != nil {
+		return err
+	}
+
+	return w.bundle.Producer.Publish(image.Event{
+		Filename: filename,
+		Mode:     config.ModeEager,
+		Metadata: &metadata,
+	})
+}